@@ -0,0 +1,18 @@
+// Package dialog shows native alerts and yes/no prompts, used to tell the
+// user why membria-helper refused to run a command, or to gate a command
+// the manifest marks as requiring confirmation.
+package dialog
+
+// Show displays message in a platform-native alert dialog titled title.
+// Failures are swallowed: a dialog that can't be shown should never crash
+// the handler, since its only purpose is informational.
+func Show(title, message string) {
+	show(title, message)
+}
+
+// Confirm shows a native yes/no prompt and reports whether the user chose
+// to proceed. If no confirmation mechanism is available, it conservatively
+// returns false rather than letting the command run unconfirmed.
+func Confirm(title, message string) bool {
+	return confirm(title, message)
+}
@@ -0,0 +1,25 @@
+//go:build darwin
+
+package dialog
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+func show(title, message string) {
+	script := fmt.Sprintf("display dialog %q with title %q buttons {\"OK\"} with icon stop", message, title)
+	_ = exec.Command("osascript", "-e", script).Run()
+}
+
+func confirm(title, message string) bool {
+	script := fmt.Sprintf(
+		"display dialog %q with title %q buttons {\"Cancel\", \"Run\"} default button \"Cancel\" cancel button \"Cancel\"",
+		message, title)
+	out, err := exec.Command("osascript", "-e", script).CombinedOutput()
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(out), "Run")
+}
@@ -0,0 +1,31 @@
+//go:build linux
+
+package dialog
+
+import "os/exec"
+
+func show(title, message string) {
+	if _, err := exec.LookPath("zenity"); err == nil {
+		_ = exec.Command("zenity", "--error", "--title", title, "--text", message).Run()
+		return
+	}
+	if _, err := exec.LookPath("kdialog"); err == nil {
+		_ = exec.Command("kdialog", "--title", title, "--error", message).Run()
+		return
+	}
+	if _, err := exec.LookPath("notify-send"); err == nil {
+		_ = exec.Command("notify-send", "-u", "critical", title, message).Run()
+	}
+}
+
+// confirm returns whether the user accepted the prompt. zenity and kdialog
+// both exit 0 for Yes/OK and non-zero for No/Cancel/closed-without-answer.
+func confirm(title, message string) bool {
+	if _, err := exec.LookPath("zenity"); err == nil {
+		return exec.Command("zenity", "--question", "--title", title, "--text", message).Run() == nil
+	}
+	if _, err := exec.LookPath("kdialog"); err == nil {
+		return exec.Command("kdialog", "--title", title, "--yesno", message).Run() == nil
+	}
+	return false
+}
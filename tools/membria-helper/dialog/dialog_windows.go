@@ -0,0 +1,27 @@
+//go:build windows
+
+package dialog
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+func show(title, message string) {
+	_ = exec.Command("msg", "*", fmt.Sprintf("%s: %s", title, message)).Run()
+}
+
+// confirm shows a Yes/No message box via PowerShell's WinForms binding,
+// since "msg" has no way to return an answer.
+func confirm(title, message string) bool {
+	script := fmt.Sprintf(
+		"Add-Type -AssemblyName System.Windows.Forms; "+
+			"[System.Windows.Forms.MessageBox]::Show('%s','%s',[System.Windows.Forms.MessageBoxButtons]::YesNo,[System.Windows.Forms.MessageBoxIcon]::Warning)",
+		strings.ReplaceAll(message, "'", "''"), strings.ReplaceAll(title, "'", "''"))
+	out, err := exec.Command("powershell", "-NoProfile", "-Command", script).CombinedOutput()
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(string(out)) == "Yes"
+}
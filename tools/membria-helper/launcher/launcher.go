@@ -0,0 +1,70 @@
+// Package launcher runs a shell command in a visible, platform-appropriate
+// terminal window on behalf of the membria-helper handler.
+package launcher
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Launcher runs a command in a terminal window.
+type Launcher interface {
+	// Run runs cmd as a shell command string.
+	Run(cmd string) error
+	// RunArgv runs argv (argv[0] is the executable) directly, without a
+	// shell - the form the manifest package's validated commands take.
+	RunArgv(argv []string) error
+	// Describe returns a short human-readable description of how Run will
+	// launch commands, for "membria doctor" to report.
+	Describe() string
+}
+
+// quoteArgv joins argv into a POSIX-shell-safe string, for platforms whose
+// terminal integration only accepts a command string rather than an argv
+// slice.
+func quoteArgv(argv []string) string {
+	quoted := make([]string, len(argv))
+	for i, a := range argv {
+		quoted[i] = "'" + strings.ReplaceAll(a, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
+
+// New returns the Launcher for the current platform. Setting MEMBRIA_TERMINAL
+// overrides auto-detection and is invoked as "$MEMBRIA_TERMINAL -e sh -c <cmd>".
+func New() Launcher {
+	if bin := os.Getenv("MEMBRIA_TERMINAL"); bin != "" {
+		return execLauncher{bin: bin, flag: "-e"}
+	}
+	return platformDefault()
+}
+
+// execLauncher shells out to a terminal emulator binary found on $PATH.
+type execLauncher struct {
+	bin  string
+	flag string
+}
+
+func (l execLauncher) Run(cmd string) error {
+	if l.bin == "" {
+		return fmt.Errorf("launcher: no terminal emulator found")
+	}
+	return exec.Command(l.bin, l.flag, "sh", "-c", cmd).Run()
+}
+
+func (l execLauncher) RunArgv(argv []string) error {
+	if l.bin == "" {
+		return fmt.Errorf("launcher: no terminal emulator found")
+	}
+	args := append([]string{l.flag}, argv...)
+	return exec.Command(l.bin, args...).Run()
+}
+
+func (l execLauncher) Describe() string {
+	if l.bin == "" {
+		return "none found"
+	}
+	return l.bin
+}
@@ -0,0 +1,30 @@
+//go:build darwin
+
+package launcher
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// appleScriptLauncher opens Terminal.app via AppleScript, the long-standing
+// behavior of membria-helper on macOS.
+type appleScriptLauncher struct{}
+
+func platformDefault() Launcher {
+	return appleScriptLauncher{}
+}
+
+func (appleScriptLauncher) Run(cmd string) error {
+	script := fmt.Sprintf("tell application \"Terminal\" to activate\n"+
+		"tell application \"Terminal\" to do script %q", cmd)
+	return exec.Command("osascript", "-e", script).Run()
+}
+
+func (a appleScriptLauncher) RunArgv(argv []string) error {
+	return a.Run(quoteArgv(argv))
+}
+
+func (appleScriptLauncher) Describe() string {
+	return "AppleScript (Terminal.app)"
+}
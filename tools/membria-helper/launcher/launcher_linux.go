@@ -0,0 +1,44 @@
+//go:build linux
+
+package launcher
+
+import (
+	"os"
+	"os/exec"
+)
+
+// linuxTerminals is the probe order used when $TERMINAL is unset or not on
+// $PATH: the desktop-environment-agnostic alternative first, then the
+// common desktop terminals, then a handful of popular standalone emulators.
+var linuxTerminals = []string{
+	"x-terminal-emulator",
+	"gnome-terminal",
+	"konsole",
+	"xterm",
+	"alacritty",
+	"kitty",
+}
+
+func platformDefault() Launcher {
+	if bin := os.Getenv("TERMINAL"); bin != "" {
+		if _, err := exec.LookPath(bin); err == nil {
+			return execLauncher{bin: bin, flag: terminalFlag(bin)}
+		}
+	}
+	for _, bin := range linuxTerminals {
+		if _, err := exec.LookPath(bin); err == nil {
+			return execLauncher{bin: bin, flag: terminalFlag(bin)}
+		}
+	}
+	return execLauncher{}
+}
+
+// terminalFlag returns the argv flag a terminal emulator expects before the
+// command to run. gnome-terminal takes a bare "--"; everything else we probe
+// for accepts "-e".
+func terminalFlag(bin string) string {
+	if bin == "gnome-terminal" {
+		return "--"
+	}
+	return "-e"
+}
@@ -0,0 +1,21 @@
+package launcher
+
+import "testing"
+
+func TestNew_HonorsMembriaTerminalOverride(t *testing.T) {
+	t.Setenv("MEMBRIA_TERMINAL", "my-term")
+
+	l, ok := New().(execLauncher)
+	if !ok {
+		t.Fatalf("New() = %T, want execLauncher", New())
+	}
+	if l.bin != "my-term" {
+		t.Fatalf("bin = %q, want %q", l.bin, "my-term")
+	}
+}
+
+func TestExecLauncher_RunWithNoBinary(t *testing.T) {
+	if err := (execLauncher{}).Run("echo hi"); err == nil {
+		t.Fatal("expected an error when no terminal emulator is configured")
+	}
+}
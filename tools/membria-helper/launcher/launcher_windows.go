@@ -0,0 +1,50 @@
+//go:build windows
+
+package launcher
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// windowsLauncher prefers Windows Terminal. Where wt.exe fails to start, Run
+// falls back to a new cmd.exe window and RunArgv launches the target process
+// directly in a new console.
+type windowsLauncher struct{}
+
+// createNewConsole is the Win32 CREATE_NEW_CONSOLE process-creation flag,
+// giving a directly-launched process its own console window.
+const createNewConsole = 0x00000010
+
+func platformDefault() Launcher {
+	return windowsLauncher{}
+}
+
+func (windowsLauncher) Run(cmd string) error {
+	if err := exec.Command("wt.exe", "powershell", "-NoExit", "-Command", cmd).Run(); err == nil {
+		return nil
+	}
+	return exec.Command("cmd", "/c", "start", "cmd", "/k", cmd).Run()
+}
+
+// RunArgv passes argv straight to exec.Command as separate arguments
+// rather than building a command string: Go's os/exec applies the correct
+// Windows argument-escaping rules for wt.exe's own argv, so no manual
+// quoting is needed or wanted - POSIX-style quoting (as quoteArgv does for
+// Run) is the wrong dialect here. The fallback below launches argv[0]
+// directly rather than going through "cmd /c start ... /k": cmd.exe is
+// itself a shell that re-interprets "&", "|", "^", "%VAR%" and friends in
+// its command line, which would reopen the shell-injection surface argv
+// exists to close if any manifest param regex is looser than it should be.
+func (w windowsLauncher) RunArgv(argv []string) error {
+	if err := exec.Command("wt.exe", argv...).Run(); err == nil {
+		return nil
+	}
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: createNewConsole}
+	return cmd.Run()
+}
+
+func (windowsLauncher) Describe() string {
+	return "Windows Terminal (wt.exe) with cmd.exe fallback"
+}
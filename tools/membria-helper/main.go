@@ -4,28 +4,336 @@ import (
 	"fmt"
 	"net/url"
 	"os"
-	"os/exec"
+	"path/filepath"
+	"sort"
 	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/actiquest-dev/membria-cli/tools/membria-helper/dialog"
+	"github.com/actiquest-dev/membria-cli/tools/membria-helper/launcher"
+	"github.com/actiquest-dev/membria-cli/tools/membria-helper/manifest"
+	"github.com/actiquest-dev/membria-cli/tools/membria-helper/register"
+	"github.com/actiquest-dev/membria-cli/tools/membria-helper/signature"
 )
 
+// newLauncher is a seam so tests can substitute a fake launcher without
+// exercising a real terminal emulator.
+var newLauncher = launcher.New
+
 func main() {
-	if len(os.Args) < 2 {
-		return
+	app := &cli.App{
+		Name:  "membria",
+		Usage: "handle membria:// links",
+		// Before this subcommand structure existed, OS handlers were
+		// registered to invoke "membria <url>" directly. Keep that working:
+		// if the sole argument parses as a membria:// URL, treat it as
+		// "membria handle <url>".
+		Action: func(c *cli.Context) error {
+			if c.NArg() == 1 {
+				if u, err := url.Parse(c.Args().Get(0)); err == nil && u.Scheme == "membria" {
+					return handleURL(c.Args().Get(0))
+				}
+			}
+			return cli.ShowAppHelp(c)
+		},
+		Commands: []*cli.Command{
+			{
+				Name:      "handle",
+				Usage:     "verify and run a membria:// URL",
+				ArgsUsage: "<url>",
+				Action: func(c *cli.Context) error {
+					if c.NArg() != 1 {
+						return cli.Exit("usage: membria handle <url>", 1)
+					}
+					return handleURL(c.Args().Get(0))
+				},
+			},
+			{
+				Name:      "sign",
+				Usage:     "produce a shareable signed membria:// URL",
+				ArgsUsage: "<cmd> | run/<id> [param=value ...]",
+				Action: func(c *cli.Context) error {
+					if c.NArg() < 1 {
+						return cli.Exit("usage: membria sign <cmd> | membria sign run/<id> [param=value ...]", 1)
+					}
+					if id, ok := strings.CutPrefix(c.Args().Get(0), "run/"); ok {
+						return runSignManifest(id, c.Args().Tail())
+					}
+					if c.NArg() != 1 {
+						return cli.Exit("usage: membria sign <cmd>", 1)
+					}
+					return runSign(c.Args().Get(0))
+				},
+			},
+			{
+				Name:      "verify",
+				Usage:     "check whether a membria:// URL carries a trusted signature, without running it",
+				ArgsUsage: "<url>",
+				Action: func(c *cli.Context) error {
+					if c.NArg() != 1 {
+						return cli.Exit("usage: membria verify <url>", 1)
+					}
+					return runVerify(c.Args().Get(0))
+				},
+			},
+			{
+				Name:  "register",
+				Usage: "register this binary as the membria:// URL handler",
+				Action: func(c *cli.Context) error {
+					return register.Register()
+				},
+			},
+			{
+				Name:  "unregister",
+				Usage: "remove this binary as the membria:// URL handler",
+				Action: func(c *cli.Context) error {
+					return register.Unregister()
+				},
+			},
+			{
+				Name:  "doctor",
+				Usage: "report the terminal, trusted signers, and URL-scheme registration status",
+				Action: func(c *cli.Context) error {
+					return runDoctor()
+				},
+			},
+			{
+				Name:  "commands",
+				Usage: "inspect the declarative command manifest",
+				Subcommands: []*cli.Command{
+					{
+						Name:  "list",
+						Usage: "print the allowed command IDs and their parameter schemas",
+						Action: func(c *cli.Context) error {
+							return runCommandsList()
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, "membria:", err)
+		os.Exit(1)
 	}
-	u, err := url.Parse(os.Args[1])
+}
+
+func handleURL(raw string) error {
+	u, err := url.Parse(raw)
 	if err != nil {
-		return
+		return fmt.Errorf("invalid URL: %w", err)
 	}
 	if u.Scheme != "membria" {
-		return
+		return fmt.Errorf("unsupported scheme %q", u.Scheme)
 	}
-	cmd := u.Query().Get("cmd")
-	cmd = strings.TrimSpace(cmd)
+
+	// membria://run/<id>?arg1=...&arg2=... looks the command up in the
+	// manifest instead of executing a freeform cmd string.
+	if u.Host == "run" {
+		return handleManifestRun(u)
+	}
+
+	cmd := strings.TrimSpace(u.Query().Get("cmd"))
 	if cmd == "" {
-		return
+		return fmt.Errorf("missing cmd parameter")
+	}
+
+	message := signature.Canonicalize(u.Scheme, u.Host, cmd)
+	if err := signature.Verify(defaultAllowedSignersPath(), u.Query().Get("signer"), message, u.Query().Get("sig")); err != nil {
+		dialog.Show("Membria", "Refusing to run unsigned or untrusted command: "+err.Error())
+		return err
+	}
+
+	return newLauncher().Run(cmd)
+}
+
+// handleManifestRun handles membria://run/<id>?arg1=...&arg2=..., looking
+// id up in the command manifest and rendering its argv template from the
+// query parameters. Unlike the legacy cmd= grammar, the resulting argv is
+// handed to the launcher directly rather than as a shell string. Like that
+// grammar, it still requires a trusted signature before anything runs: the
+// manifest only constrains *which* commands an attacker-chosen link could
+// name, it doesn't authenticate the link itself.
+func handleManifestRun(u *url.URL) error {
+	id := strings.TrimPrefix(u.Path, "/")
+	if id == "" {
+		return fmt.Errorf("missing command id")
+	}
+
+	query := u.Query()
+	signer := query.Get("signer")
+	sig := query.Get("sig")
+	query.Del("signer")
+	query.Del("sig")
+
+	params := make(map[string]string, len(query))
+	for name, values := range query {
+		params[name] = values[0]
+	}
+
+	message := signature.CanonicalizeManifestRun(id, params)
+	if err := signature.Verify(defaultAllowedSignersPath(), signer, message, sig); err != nil {
+		dialog.Show("Membria", "Refusing to run unsigned or untrusted command: "+err.Error())
+		return err
+	}
+
+	m, err := manifest.Load(defaultManifestPath())
+	if err != nil {
+		return err
+	}
+
+	argv, confirm, err := m.Render(id, params)
+	if err != nil {
+		dialog.Show("Membria", err.Error())
+		return err
+	}
+
+	if confirm && !dialog.Confirm("Membria", "Run: "+strings.Join(argv, " ")+"?") {
+		return fmt.Errorf("manifest: command %q declined by user", id)
+	}
+
+	return newLauncher().RunArgv(argv)
+}
+
+func runVerify(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+
+	signer := u.Query().Get("signer")
+	var message string
+	if u.Host == "run" {
+		query := u.Query()
+		query.Del("signer")
+		query.Del("sig")
+		params := make(map[string]string, len(query))
+		for name, values := range query {
+			params[name] = values[0]
+		}
+		message = signature.CanonicalizeManifestRun(strings.TrimPrefix(u.Path, "/"), params)
+	} else {
+		message = signature.Canonicalize(u.Scheme, u.Host, strings.TrimSpace(u.Query().Get("cmd")))
+	}
+
+	if err := signature.Verify(defaultAllowedSignersPath(), signer, message, u.Query().Get("sig")); err != nil {
+		return err
+	}
+	fmt.Printf("trusted: signed by %s\n", signer)
+	return nil
+}
+
+// runSign implements "membria sign <cmd>", printing a shareable signed
+// membria:// URL. The signing key and principal identity come from
+// MEMBRIA_SIGN_KEY / MEMBRIA_SIGNER rather than flags, since they're
+// rarely-changed per-user settings rather than per-invocation choices.
+func runSign(cmd string) error {
+	signer := os.Getenv("MEMBRIA_SIGNER")
+	if signer == "" {
+		return fmt.Errorf("MEMBRIA_SIGNER must name the principal to sign as")
+	}
+
+	keyPath := os.Getenv("MEMBRIA_SIGN_KEY")
+	if keyPath == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		keyPath = filepath.Join(home, ".ssh", "id_ed25519")
+	}
+
+	signedURL, err := signature.Sign(keyPath, signer, "", cmd)
+	if err != nil {
+		return err
+	}
+	fmt.Println(signedURL)
+	return nil
+}
+
+// runSignManifest implements "membria sign run/<id> [param=value ...]",
+// printing a shareable signed membria://run/<id> URL.
+func runSignManifest(id string, kvArgs []string) error {
+	signer := os.Getenv("MEMBRIA_SIGNER")
+	if signer == "" {
+		return fmt.Errorf("MEMBRIA_SIGNER must name the principal to sign as")
+	}
+
+	keyPath := os.Getenv("MEMBRIA_SIGN_KEY")
+	if keyPath == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		keyPath = filepath.Join(home, ".ssh", "id_ed25519")
+	}
+
+	params := make(map[string]string, len(kvArgs))
+	for _, kv := range kvArgs {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok {
+			return fmt.Errorf("invalid param %q, expected name=value", kv)
+		}
+		params[name] = value
+	}
+
+	signedURL, err := signature.SignManifestRun(keyPath, signer, id, params)
+	if err != nil {
+		return err
+	}
+	fmt.Println(signedURL)
+	return nil
+}
+
+func runDoctor() error {
+	fmt.Println("terminal:", newLauncher().Describe())
+
+	signersPath := defaultAllowedSignersPath()
+	signers, err := signature.ListSigners(signersPath)
+	if err != nil {
+		fmt.Printf("trusted signers: none (%s: %v)\n", signersPath, err)
+	} else {
+		fmt.Printf("trusted signers (%s): %s\n", signersPath, strings.Join(signers, ", "))
+	}
+
+	regStatus, err := register.Status()
+	if err != nil {
+		return err
+	}
+	fmt.Println("url scheme:", regStatus)
+	return nil
+}
+
+func runCommandsList() error {
+	m, err := manifest.Load(defaultManifestPath())
+	if err != nil {
+		return err
+	}
+	for _, id := range m.IDs() {
+		cmd := m.Commands[id]
+		params := make([]string, 0, len(cmd.Params))
+		for name, p := range cmd.Params {
+			params = append(params, fmt.Sprintf("%s=%s", name, p.Regex))
+		}
+		sort.Strings(params)
+		fmt.Printf("%s: %s (%s)\n", id, cmd.Exec, strings.Join(params, ", "))
+	}
+	return nil
+}
+
+func defaultAllowedSignersPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "membria", "allowed_signers")
+}
+
+func defaultManifestPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
 	}
-	// Open Terminal and run command
-	script := fmt.Sprintf("tell application \"Terminal\" to activate\n"+
-		"tell application \"Terminal\" to do script %q", cmd)
-	_ = exec.Command("osascript", "-e", script).Run()
+	return filepath.Join(home, ".config", "membria", "commands.toml")
 }
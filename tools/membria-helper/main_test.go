@@ -0,0 +1,93 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/actiquest-dev/membria-cli/tools/membria-helper/launcher"
+)
+
+// fakeLauncher records whether it was asked to run anything, so tests can
+// assert that a rejected URL never reaches the launcher.
+type fakeLauncher struct {
+	ran     bool
+	ranArgv bool
+}
+
+func (f *fakeLauncher) Run(cmd string) error        { f.ran = true; return nil }
+func (f *fakeLauncher) RunArgv(argv []string) error { f.ranArgv = true; return nil }
+func (f *fakeLauncher) Describe() string            { return "fake" }
+
+func withFakeLauncher(t *testing.T) *fakeLauncher {
+	t.Helper()
+	f := &fakeLauncher{}
+	original := newLauncher
+	newLauncher = func() launcher.Launcher { return f }
+	t.Cleanup(func() { newLauncher = original })
+	return f
+}
+
+func TestHandleURL_RejectsInvalidURL(t *testing.T) {
+	f := withFakeLauncher(t)
+
+	if err := handleURL("membria://%zz"); err == nil {
+		t.Fatal("expected a parse error")
+	}
+	if f.ran || f.ranArgv {
+		t.Fatal("launcher should not run for an unparseable URL")
+	}
+}
+
+func TestHandleURL_RejectsNonMembriaScheme(t *testing.T) {
+	f := withFakeLauncher(t)
+
+	if err := handleURL("https://example.com?cmd=ls"); err == nil {
+		t.Fatal("expected an error for a non-membria scheme")
+	}
+	if f.ran || f.ranArgv {
+		t.Fatal("launcher should not run for a rejected scheme")
+	}
+}
+
+func TestHandleURL_RejectsMissingCmd(t *testing.T) {
+	f := withFakeLauncher(t)
+
+	if err := handleURL("membria://?signer=alice&sig=AAAA"); err == nil {
+		t.Fatal("expected an error for a missing cmd parameter")
+	}
+	if f.ran {
+		t.Fatal("launcher should not run without a cmd")
+	}
+}
+
+func TestHandleURL_RejectsUnsignedCmd(t *testing.T) {
+	f := withFakeLauncher(t)
+
+	if err := handleURL("membria://?cmd=ls"); err == nil {
+		t.Fatal("expected an error for a cmd with no signature")
+	}
+	if f.ran {
+		t.Fatal("launcher should not run an unsigned command")
+	}
+}
+
+func TestHandleManifestRun_RejectsMissingID(t *testing.T) {
+	f := withFakeLauncher(t)
+
+	if err := handleURL("membria://run?signer=alice&sig=AAAA"); err == nil {
+		t.Fatal("expected an error for a missing command id")
+	}
+	if f.ranArgv {
+		t.Fatal("launcher should not run without a command id")
+	}
+}
+
+func TestHandleManifestRun_RejectsUnsignedRequest(t *testing.T) {
+	f := withFakeLauncher(t)
+
+	if err := handleURL("membria://run/restart-agent?service=web"); err == nil {
+		t.Fatal("expected an error for an unsigned manifest run")
+	}
+	if f.ranArgv {
+		t.Fatal("launcher should not run an unsigned manifest command")
+	}
+}
@@ -0,0 +1,96 @@
+// Package manifest loads the declarative command allowlist membria-helper
+// consults for membria://run/<id> URLs, mapping short command IDs to argv
+// templates with typed, regex-validated parameters.
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"text/template"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Param declares one named, regex-validated parameter a Command's argv
+// template may reference as {{.name}}.
+type Param struct {
+	Regex string `toml:"regex"`
+}
+
+// Command is one allowed command: a fixed executable plus an argv template
+// rendered from validated parameters.
+type Command struct {
+	Exec    string           `toml:"exec"`
+	Args    []string         `toml:"args"`
+	Params  map[string]Param `toml:"params"`
+	Confirm bool             `toml:"confirm"`
+}
+
+// Manifest is the parsed contents of commands.toml: command ID -> Command.
+type Manifest struct {
+	Commands map[string]Command `toml:"commands"`
+}
+
+// Load reads and parses the manifest at path.
+func Load(path string) (*Manifest, error) {
+	var m Manifest
+	if _, err := toml.DecodeFile(path, &m); err != nil {
+		return nil, fmt.Errorf("manifest: %w", err)
+	}
+	return &m, nil
+}
+
+// IDs returns the manifest's command IDs, sorted.
+func (m *Manifest) IDs() []string {
+	ids := make([]string, 0, len(m.Commands))
+	for id := range m.Commands {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Render validates params against each of id's declared Param regexes and
+// expands its argv template, returning the literal argv to execute - never
+// a shell string, so there is no shell for an untrusted param to inject
+// into. The second return value reports whether the manifest requires
+// confirmation before running this command.
+func (m *Manifest) Render(id string, params map[string]string) ([]string, bool, error) {
+	cmd, ok := m.Commands[id]
+	if !ok {
+		return nil, false, fmt.Errorf("manifest: unknown command %q", id)
+	}
+
+	// Only params declared (and validated) here reach the template: an
+	// {{.x}} placeholder whose param a manifest author forgot to declare
+	// must fail to render rather than receive an unvalidated raw value.
+	data := make(map[string]string, len(cmd.Params))
+	for name, p := range cmd.Params {
+		re, err := regexp.Compile("^(?:" + p.Regex + ")$")
+		if err != nil {
+			return nil, false, fmt.Errorf("manifest: command %q param %q: invalid regex: %w", id, name, err)
+		}
+		value, ok := params[name]
+		if !ok || !re.MatchString(value) {
+			return nil, false, fmt.Errorf("manifest: command %q param %q value %q does not match %s", id, name, value, p.Regex)
+		}
+		data[name] = value
+	}
+
+	argv := make([]string, 0, len(cmd.Args)+1)
+	argv = append(argv, cmd.Exec)
+	for _, argTpl := range cmd.Args {
+		tpl, err := template.New(id).Option("missingkey=error").Parse(argTpl)
+		if err != nil {
+			return nil, false, fmt.Errorf("manifest: command %q: invalid arg template %q: %w", id, argTpl, err)
+		}
+		var buf bytes.Buffer
+		if err := tpl.Execute(&buf, data); err != nil {
+			return nil, false, fmt.Errorf("manifest: command %q: %w", id, err)
+		}
+		argv = append(argv, buf.String())
+	}
+	return argv, cmd.Confirm, nil
+}
@@ -0,0 +1,72 @@
+package manifest
+
+import "testing"
+
+func testManifest() *Manifest {
+	return &Manifest{
+		Commands: map[string]Command{
+			"restart-agent": {
+				Exec: "systemctl",
+				Args: []string{"restart", "{{.service}}"},
+				Params: map[string]Param{
+					"service": {Regex: "[a-zA-Z0-9_-]+"},
+				},
+			},
+		},
+	}
+}
+
+func TestRender_AnchorsUnanchoredRegex(t *testing.T) {
+	m := testManifest()
+
+	// "[a-zA-Z0-9_-]+" is unanchored; without implicit anchoring a value
+	// like "web; rm -rf /" would match on the "web" substring alone.
+	if _, _, err := m.Render("restart-agent", map[string]string{"service": "web; rm -rf /"}); err == nil {
+		t.Fatal("expected an unanchored regex to still reject a value with extra characters")
+	}
+}
+
+func TestRender_RejectsUndeclaredParams(t *testing.T) {
+	m := testManifest()
+	m.Commands["restart-agent"] = Command{
+		Exec: "systemctl",
+		Args: []string{"restart", "{{.service}}", "{{.extra}}"},
+		Params: map[string]Param{
+			"service": {Regex: "[a-zA-Z0-9_-]+"},
+		},
+	}
+
+	// "extra" is used in the template but never declared under Params, so
+	// it must never receive the raw, unvalidated query value.
+	if _, _, err := m.Render("restart-agent", map[string]string{"service": "web", "extra": "anything"}); err == nil {
+		t.Fatal("expected rendering to fail for an undeclared template parameter")
+	}
+}
+
+func TestRender_Success(t *testing.T) {
+	m := testManifest()
+
+	argv, confirm, err := m.Render("restart-agent", map[string]string{"service": "web"})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if confirm {
+		t.Fatal("confirm should be false by default")
+	}
+	want := []string{"systemctl", "restart", "web"}
+	if len(argv) != len(want) {
+		t.Fatalf("argv = %v, want %v", argv, want)
+	}
+	for i := range want {
+		if argv[i] != want[i] {
+			t.Fatalf("argv = %v, want %v", argv, want)
+		}
+	}
+}
+
+func TestRender_UnknownCommand(t *testing.T) {
+	m := testManifest()
+	if _, _, err := m.Render("does-not-exist", nil); err == nil {
+		t.Fatal("expected an error for an unknown command id")
+	}
+}
@@ -0,0 +1,22 @@
+// Package register installs and inspects the OS URL-scheme handler that
+// maps membria:// links to this binary.
+package register
+
+// Register installs this binary as the OS handler for the membria://
+// scheme.
+func Register() error {
+	return register()
+}
+
+// Unregister removes this binary as the OS handler for the membria://
+// scheme.
+func Unregister() error {
+	return unregister()
+}
+
+// Status reports whether the membria:// handler entry that Register writes
+// (a file, or on Windows a registry key) is present. It does not check
+// which binary that entry points at.
+func Status() (string, error) {
+	return status()
+}
@@ -0,0 +1,71 @@
+//go:build darwin
+
+package register
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// darwinHandlerPlist is a minimal CFBundleURLTypes fragment recording that
+// this binary owns the membria scheme; membria-helper ships as a bare
+// binary rather than an app bundle, so it is written under
+// ~/Library/Application Support/membria instead of inside an Info.plist.
+const darwinHandlerPlist = `{
+  CFBundleURLTypes = (
+    {
+      CFBundleURLName = "Membria";
+      CFBundleURLSchemes = ( "membria" );
+    }
+  );
+}`
+
+func handlerPlistPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "Library", "Application Support", "membria", "membria-handler.plist"), nil
+}
+
+func register() error {
+	path, err := handlerPlistPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(darwinHandlerPlist), 0o644); err != nil {
+		return err
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	return exec.Command(
+		"/System/Library/Frameworks/CoreServices.framework/Frameworks/LaunchServices.framework/Support/lsregister",
+		"-f", exe,
+	).Run()
+}
+
+func unregister() error {
+	path, err := handlerPlistPath()
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+func status() (string, error) {
+	path, err := handlerPlistPath()
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "not registered", nil
+	}
+	return "registered (" + path + ")", nil
+}
@@ -0,0 +1,70 @@
+//go:build linux
+
+package register
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+func desktopFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share", "applications", "membria.desktop"), nil
+}
+
+func register() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	path, err := desktopFilePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	contents := fmt.Sprintf(`[Desktop Entry]
+Type=Application
+Name=Membria
+Exec=%s handle %%u
+NoDisplay=true
+MimeType=x-scheme-handler/membria;
+`, exe)
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		return err
+	}
+
+	if _, err := exec.LookPath("update-desktop-database"); err == nil {
+		_ = exec.Command("update-desktop-database", filepath.Dir(path)).Run()
+	}
+	if _, err := exec.LookPath("xdg-mime"); err == nil {
+		_ = exec.Command("xdg-mime", "default", "membria.desktop", "x-scheme-handler/membria").Run()
+	}
+	return nil
+}
+
+func unregister() error {
+	path, err := desktopFilePath()
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+func status() (string, error) {
+	path, err := desktopFilePath()
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "not registered", nil
+	}
+	return "registered (" + path + ")", nil
+}
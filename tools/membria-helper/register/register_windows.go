@@ -0,0 +1,43 @@
+//go:build windows
+
+package register
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+const classKey = `HKCU\Software\Classes\membria`
+
+func register() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	commands := [][]string{
+		{"add", classKey, "/ve", "/d", "URL:Membria Protocol", "/f"},
+		{"add", classKey, "/v", "URL Protocol", "/d", "", "/f"},
+		{"add", classKey + `\shell\open\command`, "/ve", "/d", fmt.Sprintf(`"%s" handle "%%1"`, exe), "/f"},
+	}
+	for _, args := range commands {
+		if out, err := exec.Command("reg", args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("register: %w: %s", err, out)
+		}
+	}
+	return nil
+}
+
+func unregister() error {
+	if out, err := exec.Command("reg", "delete", classKey, "/f").CombinedOutput(); err != nil {
+		return fmt.Errorf("unregister: %w: %s", err, out)
+	}
+	return nil
+}
+
+func status() (string, error) {
+	if err := exec.Command("reg", "query", classKey).Run(); err != nil {
+		return "not registered", nil
+	}
+	return "registered (" + classKey + ")", nil
+}
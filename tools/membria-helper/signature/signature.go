@@ -0,0 +1,184 @@
+// Package signature verifies and produces SSH-signed membria:// commands,
+// using the same armored detached-signature format and verification flow
+// as "ssh-keygen -Y sign" / "ssh-keygen -Y verify".
+package signature
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// Namespace is the SSH signature namespace membria commands are signed
+// under, matching the -n flag of "ssh-keygen -Y sign/verify".
+const Namespace = "membria"
+
+// Canonicalize produces the exact byte string that must be signed for a
+// membria:// command: scheme and host lowercased, cmd trimmed of
+// surrounding whitespace.
+func Canonicalize(scheme, host, cmd string) string {
+	return strings.ToLower(scheme) + "://" + strings.ToLower(host) + "?cmd=" + strings.TrimSpace(cmd)
+}
+
+// CanonicalizeManifestRun produces the exact byte string that must be
+// signed for a membria://run/<id> command: the command id followed by its
+// parameters sorted by name, so the signature covers every argument the
+// manifest will render into argv.
+func CanonicalizeManifestRun(id string, params map[string]string) string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("run/")
+	b.WriteString(id)
+	for _, name := range names {
+		b.WriteByte('&')
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(params[name])
+	}
+	return b.String()
+}
+
+// Verify checks that sig (base64-encoded armored SSH signature) is a valid
+// detached signature over message, produced by signer and trusted per
+// allowedSignersPath (the format "ssh-keygen -Y verify -f" expects). It
+// returns nil only if verification succeeds.
+func Verify(allowedSignersPath, signer, message, sig string) error {
+	if signer == "" {
+		return fmt.Errorf("signature: missing signer parameter")
+	}
+	if sig == "" {
+		return fmt.Errorf("signature: missing sig parameter")
+	}
+	if _, err := os.Stat(allowedSignersPath); err != nil {
+		return fmt.Errorf("signature: allowed_signers not found: %w", err)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(sig)
+	if err != nil {
+		return fmt.Errorf("signature: sig is not valid base64: %w", err)
+	}
+
+	sigFile, err := os.CreateTemp("", "membria-sig-*.sig")
+	if err != nil {
+		return fmt.Errorf("signature: %w", err)
+	}
+	defer os.Remove(sigFile.Name())
+	if _, err := sigFile.Write(raw); err != nil {
+		sigFile.Close()
+		return fmt.Errorf("signature: %w", err)
+	}
+	if err := sigFile.Close(); err != nil {
+		return fmt.Errorf("signature: %w", err)
+	}
+
+	cmd := exec.Command("ssh-keygen", "-Y", "verify",
+		"-f", allowedSignersPath,
+		"-I", signer,
+		"-n", Namespace,
+		"-s", sigFile.Name())
+	cmd.Stdin = strings.NewReader(message)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("signature: verification failed: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Sign produces a signed membria:// URL invoking cmd on host, using the
+// private key at keyPath to sign over the canonical message. signer is the
+// principal identity recorded in the URL; it must match an entry in the
+// recipient's allowed_signers file for Verify to accept it.
+func Sign(keyPath, signer, host, cmd string) (string, error) {
+	sigBytes, err := signMessage(keyPath, Canonicalize("membria", host, cmd))
+	if err != nil {
+		return "", err
+	}
+
+	values := url.Values{}
+	values.Set("cmd", cmd)
+	values.Set("signer", signer)
+	values.Set("sig", base64.StdEncoding.EncodeToString(sigBytes))
+
+	signed := url.URL{Scheme: "membria", Host: host, RawQuery: values.Encode()}
+	return signed.String(), nil
+}
+
+// SignManifestRun produces a signed membria://run/<id> URL for the given
+// params, using the private key at keyPath. signer is the principal
+// identity recorded in the URL; it must match an entry in the recipient's
+// allowed_signers file for Verify to accept it.
+func SignManifestRun(keyPath, signer, id string, params map[string]string) (string, error) {
+	sigBytes, err := signMessage(keyPath, CanonicalizeManifestRun(id, params))
+	if err != nil {
+		return "", err
+	}
+
+	values := url.Values{}
+	for name, value := range params {
+		values.Set(name, value)
+	}
+	values.Set("signer", signer)
+	values.Set("sig", base64.StdEncoding.EncodeToString(sigBytes))
+
+	signed := url.URL{Scheme: "membria", Host: "run", Path: "/" + id, RawQuery: values.Encode()}
+	return signed.String(), nil
+}
+
+// signMessage runs "ssh-keygen -Y sign" over message using the private key
+// at keyPath and returns the resulting armored detached signature.
+func signMessage(keyPath, message string) ([]byte, error) {
+	msgFile, err := os.CreateTemp("", "membria-msg-*")
+	if err != nil {
+		return nil, fmt.Errorf("signature: %w", err)
+	}
+	defer os.Remove(msgFile.Name())
+	if _, err := msgFile.WriteString(message); err != nil {
+		msgFile.Close()
+		return nil, fmt.Errorf("signature: %w", err)
+	}
+	if err := msgFile.Close(); err != nil {
+		return nil, fmt.Errorf("signature: %w", err)
+	}
+
+	out, err := exec.Command("ssh-keygen", "-Y", "sign", "-n", Namespace, "-f", keyPath, msgFile.Name()).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("signature: sign failed: %s", strings.TrimSpace(string(out)))
+	}
+	defer os.Remove(msgFile.Name() + ".sig")
+
+	sigBytes, err := os.ReadFile(msgFile.Name() + ".sig")
+	if err != nil {
+		return nil, fmt.Errorf("signature: %w", err)
+	}
+	return sigBytes, nil
+}
+
+// ListSigners returns the principal identities listed in the
+// allowed_signers file at path, one per entry, in file order.
+func ListSigners(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var principals []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		principals = append(principals, fields[0])
+	}
+	return principals, nil
+}
@@ -0,0 +1,138 @@
+package signature
+
+import (
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testSigner generates an ephemeral ed25519 key pair in t.TempDir and an
+// allowed_signers file trusting it under principal, returning the private
+// key path and the allowed_signers path.
+func testSigner(t *testing.T, principal string) (keyPath, allowedSignersPath string) {
+	t.Helper()
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not available")
+	}
+
+	dir := t.TempDir()
+	keyPath = filepath.Join(dir, "id_ed25519")
+	if out, err := exec.Command("ssh-keygen", "-t", "ed25519", "-N", "", "-C", "", "-f", keyPath).CombinedOutput(); err != nil {
+		t.Fatalf("ssh-keygen -t ed25519: %v: %s", err, out)
+	}
+
+	pub, err := os.ReadFile(keyPath + ".pub")
+	if err != nil {
+		t.Fatalf("read public key: %v", err)
+	}
+
+	allowedSignersPath = filepath.Join(dir, "allowed_signers")
+	line := principal + " " + strings.TrimSpace(string(pub)) + "\n"
+	if err := os.WriteFile(allowedSignersPath, []byte(line), 0o600); err != nil {
+		t.Fatalf("write allowed_signers: %v", err)
+	}
+	return keyPath, allowedSignersPath
+}
+
+func TestSignVerify_Cmd(t *testing.T) {
+	keyPath, allowedSignersPath := testSigner(t, "alice")
+
+	signedURL, err := Sign(keyPath, "alice", "", "restart web")
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	u, err := url.Parse(signedURL)
+	if err != nil {
+		t.Fatalf("url.Parse: %v", err)
+	}
+
+	message := Canonicalize(u.Scheme, u.Host, u.Query().Get("cmd"))
+	if err := Verify(allowedSignersPath, u.Query().Get("signer"), message, u.Query().Get("sig")); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+}
+
+// TestSignVerify_HostMustMatch pins the host-mismatch bug class that let
+// every "membria sign <cmd>" URL fail to verify: Sign's host argument must
+// be the exact host Verify's caller later canonicalizes against.
+func TestSignVerify_HostMustMatch(t *testing.T) {
+	keyPath, allowedSignersPath := testSigner(t, "alice")
+
+	signedURL, err := Sign(keyPath, "alice", "", "restart web")
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	u, err := url.Parse(signedURL)
+	if err != nil {
+		t.Fatalf("url.Parse: %v", err)
+	}
+
+	message := Canonicalize(u.Scheme, "run", u.Query().Get("cmd"))
+	if err := Verify(allowedSignersPath, u.Query().Get("signer"), message, u.Query().Get("sig")); err == nil {
+		t.Fatal("expected Verify to reject a signature canonicalized against the wrong host")
+	}
+}
+
+func TestSignVerify_ManifestRun(t *testing.T) {
+	keyPath, allowedSignersPath := testSigner(t, "alice")
+
+	params := map[string]string{"service": "web"}
+	signedURL, err := SignManifestRun(keyPath, "alice", "restart-agent", params)
+	if err != nil {
+		t.Fatalf("SignManifestRun: %v", err)
+	}
+
+	u, err := url.Parse(signedURL)
+	if err != nil {
+		t.Fatalf("url.Parse: %v", err)
+	}
+	query := u.Query()
+	signer := query.Get("signer")
+	sig := query.Get("sig")
+	query.Del("signer")
+	query.Del("sig")
+	got := make(map[string]string, len(query))
+	for name, values := range query {
+		got[name] = values[0]
+	}
+
+	message := CanonicalizeManifestRun(strings.TrimPrefix(u.Path, "/"), got)
+	if err := Verify(allowedSignersPath, signer, message, sig); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+}
+
+func TestVerify_RejectsUntrustedSigner(t *testing.T) {
+	keyPath, _ := testSigner(t, "alice")
+	_, otherAllowedSigners := testSigner(t, "bob")
+
+	signedURL, err := Sign(keyPath, "alice", "", "restart web")
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	u, err := url.Parse(signedURL)
+	if err != nil {
+		t.Fatalf("url.Parse: %v", err)
+	}
+
+	message := Canonicalize(u.Scheme, u.Host, u.Query().Get("cmd"))
+	if err := Verify(otherAllowedSigners, u.Query().Get("signer"), message, u.Query().Get("sig")); err == nil {
+		t.Fatal("expected Verify to reject a signer not present in allowed_signers")
+	}
+}
+
+func TestListSigners(t *testing.T) {
+	_, allowedSignersPath := testSigner(t, "alice")
+
+	signers, err := ListSigners(allowedSignersPath)
+	if err != nil {
+		t.Fatalf("ListSigners: %v", err)
+	}
+	if len(signers) != 1 || signers[0] != "alice" {
+		t.Fatalf("ListSigners = %v, want [alice]", signers)
+	}
+}